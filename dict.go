@@ -0,0 +1,188 @@
+package wasmecdict
+
+import (
+	"bytes"
+	_ "embed"
+	"fmt"
+	"index/suffixarray"
+	"io"
+	"log"
+	"strings"
+	"sync"
+	"unsafe"
+)
+
+//go:embed ecdict.csv
+var ecdictCsv []byte
+
+//go:embed lemma.en.txt
+var lemmaEnTxt []byte
+
+// Dict is a self-contained ECDICT-style dictionary: a headword map plus a
+// lemma (inflected -> base) map and its reverse. The package-level
+// functions (LookUp, Tokenize, SearchPrefix, ...) are a thin wrapper
+// around defaultDict, a Dict built from the embedded ecdict.csv and
+// lemma.en.txt, so callers that don't need a custom vocabulary can ignore
+// Dict entirely.
+type Dict struct {
+	mu           sync.RWMutex
+	dictMap      map[string]DictItem
+	lemmaMap     map[string]string
+	lemmaReverse map[string][]string
+	lemmaEntries map[string]*LemmaEntry
+
+	// suffixIndex and friends are the cached search.go suffix-array index.
+	// They're guarded by mu like everything else above: ensureSuffixIndex
+	// takes mu to build them, resetSuffixIndexLocked (called by AddEntry
+	// and Merge, already holding mu) clears them, and SearchPrefix /
+	// SearchSubstring hold mu.RLock while reading them.
+	suffixIndex  *suffixarray.Index
+	suffixStarts []int
+	suffixWords  []string
+}
+
+// NewDict builds a Dict from a reader in ECDICT's CSV column layout and a
+// lemma.en.txt-formatted reader. Either reader may be nil to build an
+// empty side, e.g. a lemma-only or dict-only Dict meant to be layered onto
+// another one with Merge.
+func NewDict(dictCSV io.Reader, lemma io.Reader) (*Dict, error) {
+	d := &Dict{
+		dictMap:      map[string]DictItem{},
+		lemmaMap:     map[string]string{},
+		lemmaReverse: map[string][]string{},
+		lemmaEntries: map[string]*LemmaEntry{},
+	}
+	if dictCSV != nil {
+		dictMap, err := parseDict(dictCSV)
+		if err != nil {
+			return nil, fmt.Errorf("wasmecdict: parse dict csv: %w", err)
+		}
+		d.dictMap = dictMap
+	}
+	if lemma != nil {
+		lemmaBytes, err := io.ReadAll(lemma)
+		if err != nil {
+			return nil, fmt.Errorf("wasmecdict: read lemma source: %w", err)
+		}
+		d.lemmaMap, d.lemmaReverse, d.lemmaEntries = parseLemma(bytes.NewReader(lemmaBytes))
+	}
+	return d, nil
+}
+
+// AddEntry inserts or overwrites a single dictionary entry, indexed by
+// both its original casing and its lower-cased form. It invalidates the
+// cached search index so a subsequent SearchPrefix/SearchSubstring picks
+// up the change.
+func (d *Dict) AddEntry(item DictItem) {
+	d.mu.Lock()
+	defer d.mu.Unlock()
+	d.dictMap[item.Word] = item
+	d.dictMap[strings.ToLower(item.Word)] = item
+	d.resetSuffixIndexLocked()
+}
+
+// Merge layers other's entries on top of d: every dict entry and lemma
+// mapping in other overwrites or extends the corresponding entry in d.
+// This lets a caller stack a custom vocabulary on top of the embedded
+// ECDICT without recompiling.
+func (d *Dict) Merge(other *Dict) {
+	if other == nil || other == d {
+		return
+	}
+	unlock := lockOrdered(d, other)
+	defer unlock()
+
+	for word, item := range other.dictMap {
+		d.dictMap[word] = item
+	}
+	for inflected, base := range other.lemmaMap {
+		d.lemmaMap[inflected] = base
+	}
+	for base, forms := range other.lemmaReverse {
+		d.lemmaReverse[base] = append(d.lemmaReverse[base], forms...)
+	}
+	for base, entry := range other.lemmaEntries {
+		d.lemmaEntries[base] = entry
+	}
+	d.resetSuffixIndexLocked()
+}
+
+// lockOrdered locks d for writing and other for reading, in a pointer-
+// address order that's the same regardless of which Dict Merge was called
+// on. Without this, a.Merge(b) running concurrently with b.Merge(a) could
+// each acquire their own d.mu.Lock() and then block forever on the other's
+// mu, a classic AB-BA deadlock.
+func lockOrdered(d, other *Dict) (unlock func()) {
+	if uintptr(unsafe.Pointer(d)) < uintptr(unsafe.Pointer(other)) {
+		d.mu.Lock()
+		other.mu.RLock()
+		return func() {
+			other.mu.RUnlock()
+			d.mu.Unlock()
+		}
+	}
+	other.mu.RLock()
+	d.mu.Lock()
+	return func() {
+		d.mu.Unlock()
+		other.mu.RUnlock()
+	}
+}
+
+// resetSuffixIndexLocked drops the cached suffix search index so it gets
+// rebuilt, lazily, against the current dictMap. Callers must hold d.mu for
+// writing.
+func (d *Dict) resetSuffixIndexLocked() {
+	d.suffixIndex = nil
+	d.suffixStarts = nil
+	d.suffixWords = nil
+}
+
+// ensureSuffixIndex builds the suffix search index used by SearchPrefix
+// and SearchSubstring if it hasn't been built yet, or was invalidated by a
+// prior AddEntry/Merge. It synchronizes on d.mu so building can't race
+// with a concurrent mutation of dictMap or of the index fields themselves.
+func (d *Dict) ensureSuffixIndex() {
+	d.mu.RLock()
+	ready := d.suffixIndex != nil
+	d.mu.RUnlock()
+	if ready {
+		return
+	}
+
+	d.mu.Lock()
+	defer d.mu.Unlock()
+	if d.suffixIndex != nil {
+		return // built by another goroutine while we waited for the lock
+	}
+	d.buildSuffixIndexLocked()
+}
+
+// withSuffixIndex ensures the suffix index is built, then runs fn while
+// holding d.mu for reading and returns its result. If AddEntry or Merge
+// invalidates the index between ensureSuffixIndex returning and
+// withSuffixIndex acquiring the read lock, it rebuilds and retries rather
+// than handing fn a nil index.
+func (d *Dict) withSuffixIndex(fn func() map[string]bool) map[string]bool {
+	for {
+		d.ensureSuffixIndex()
+		d.mu.RLock()
+		if d.suffixIndex == nil {
+			d.mu.RUnlock()
+			continue
+		}
+		matches := fn()
+		d.mu.RUnlock()
+		return matches
+	}
+}
+
+var defaultDict = mustDefaultDict()
+
+func mustDefaultDict() *Dict {
+	d, err := NewDict(bytes.NewReader(ecdictCsv), bytes.NewReader(lemmaEnTxt))
+	if err != nil {
+		log.Fatal(err)
+	}
+	return d
+}
@@ -0,0 +1,313 @@
+package wasmecdict
+
+import (
+	"bytes"
+	"encoding/json"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+)
+
+const testDictCSV = `word,phonetic,definition,translation,pos,collins,oxford,tag,bnc,frq,exchange,detail,audio
+run,/rʌn/,to move fast,跑,v,1,1,zk,100,200,p:ran/d:run/i:running/3:runs/0:run,,
+jump,/dʒʌmp/,to leap,跳,v,1,0,zk,150,250,,,
+`
+
+const testLemmaTxt = `; comment line
+run/500 -> ran@,running,runs
+jump -> jumped,jumping,jumps
+malformed line with no arrow
+`
+
+func newTestDict(t *testing.T) *Dict {
+	t.Helper()
+	d, err := NewDict(strings.NewReader(testDictCSV), strings.NewReader(testLemmaTxt))
+	if err != nil {
+		t.Fatalf("NewDict: %v", err)
+	}
+	return d
+}
+
+func TestNewDictLookUp(t *testing.T) {
+	d := newTestDict(t)
+
+	if item := d.LookUp("run"); item == nil || item.Translation != "跑" {
+		t.Fatalf("LookUp(%q) = %+v, want a match for 跑", "run", item)
+	}
+	if item := d.LookUp("ran"); item == nil || item.Word != "run" {
+		t.Fatalf("LookUp(%q) should resolve the inflected form to its base, got %+v", "ran", item)
+	}
+	if item := d.LookUp("nonexistent"); item != nil {
+		t.Fatalf("LookUp(%q) = %+v, want nil", "nonexistent", item)
+	}
+}
+
+func TestLookUpWithOptionsExchange(t *testing.T) {
+	d := newTestDict(t)
+
+	item := d.LookUpWithOptions("run", LookUpOptions{IncludeExchange: true})
+	if item == nil {
+		t.Fatal("LookUpWithOptions(\"run\") = nil")
+	}
+	if got, want := item.ExchangeMap["p"], "ran"; got != want {
+		t.Errorf("ExchangeMap[p] = %q, want %q", got, want)
+	}
+	if item.ExchangeMap["i"] != "running" {
+		t.Errorf("ExchangeMap[i] = %q, want %q", item.ExchangeMap["i"], "running")
+	}
+
+	if item := d.LookUp("run"); item.ExchangeMap != nil {
+		t.Errorf("plain LookUp should not populate ExchangeMap, got %v", item.ExchangeMap)
+	}
+}
+
+func TestFormsAndLemma(t *testing.T) {
+	d := newTestDict(t)
+
+	forms := d.Forms("run")
+	want := map[string]bool{"ran": true, "running": true, "runs": true}
+	if len(forms) != len(want) {
+		t.Fatalf("Forms(run) = %v, want %d entries matching %v", forms, len(want), want)
+	}
+	for _, f := range forms {
+		if !want[f] {
+			t.Errorf("Forms(run) contained unexpected form %q", f)
+		}
+	}
+
+	entry := d.Lemma("run")
+	if entry == nil {
+		t.Fatal("Lemma(run) = nil")
+	}
+	if entry.Rank != 500 {
+		t.Errorf("Lemma(run).Rank = %d, want 500", entry.Rank)
+	}
+	var sawIrregular bool
+	for _, f := range entry.Forms {
+		if f.Text == "ran" {
+			sawIrregular = f.Irregular
+		}
+	}
+	if !sawIrregular {
+		t.Errorf("Lemma(run).Forms should mark %q irregular", "ran")
+	}
+}
+
+func TestSearchPrefixAndSubstring(t *testing.T) {
+	d := newTestDict(t)
+
+	prefixResults := d.SearchPrefix("ru", 10)
+	if len(prefixResults) != 1 || prefixResults[0].Word != "run" {
+		t.Fatalf("SearchPrefix(ru) = %+v, want just [run]", prefixResults)
+	}
+
+	substrResults := d.SearchSubstring("um", 10)
+	if len(substrResults) != 1 || substrResults[0].Word != "jump" {
+		t.Fatalf("SearchSubstring(um) = %+v, want just [jump]", substrResults)
+	}
+
+	if got := d.SearchPrefix("ru", 0); got != nil {
+		t.Errorf("SearchPrefix with limit 0 = %v, want nil", got)
+	}
+}
+
+func TestSearchPrefixRespectsLimit(t *testing.T) {
+	d, err := NewDict(nil, nil)
+	if err != nil {
+		t.Fatalf("NewDict: %v", err)
+	}
+	for i := 0; i < 50; i++ {
+		d.AddEntry(DictItem{Word: strings.Repeat("z", 1) + string(rune('a'+i%26)) + "x"})
+	}
+	got := d.SearchPrefix("z", 5)
+	if len(got) > 5 {
+		t.Fatalf("SearchPrefix returned %d entries, want at most the requested 5", len(got))
+	}
+}
+
+// TestSearchPrefixClampsHugeLimit guards against a caller-supplied limit
+// being used directly as an allocation size: a WASM/JS caller can pass an
+// arbitrarily large number, and collectItems must never try to size a
+// slice to it.
+func TestSearchPrefixClampsHugeLimit(t *testing.T) {
+	d := newTestDict(t)
+	got := d.SearchPrefix("r", 1<<30)
+	if len(got) > maxSearchLimit {
+		t.Fatalf("SearchPrefix returned %d entries, want at most maxSearchLimit (%d)", len(got), maxSearchLimit)
+	}
+}
+
+func TestTokenize(t *testing.T) {
+	d := newTestDict(t)
+
+	tokens := d.Tokenize("I run, don't jump 123 times.")
+	var surfaces []string
+	for _, tok := range tokens {
+		surfaces = append(surfaces, tok.Surface)
+	}
+	want := []string{"I", "run", "don", "t", "jump", "times"}
+	if len(surfaces) != len(want) {
+		t.Fatalf("Tokenize surfaces = %v, want %v", surfaces, want)
+	}
+	for i, w := range want {
+		if surfaces[i] != w {
+			t.Errorf("token %d = %q, want %q", i, surfaces[i], w)
+		}
+	}
+
+	for _, tok := range tokens {
+		if tok.Surface == "run" {
+			if tok.Entry == nil || tok.Entry.Word != "run" {
+				t.Errorf("Tokenize should resolve %q against the dictionary, got %+v", "run", tok.Entry)
+			}
+		}
+	}
+}
+
+func TestExportJSONAndNDJSON(t *testing.T) {
+	d := newTestDict(t)
+
+	var jsonBuf bytes.Buffer
+	if err := d.ExportJSON(&jsonBuf, ExportOptions{}); err != nil {
+		t.Fatalf("ExportJSON: %v", err)
+	}
+	var items []exportItem
+	if err := json.Unmarshal(jsonBuf.Bytes(), &items); err != nil {
+		t.Fatalf("ExportJSON produced invalid JSON: %v", err)
+	}
+	if len(items) != 2 {
+		t.Fatalf("ExportJSON exported %d entries, want 2", len(items))
+	}
+
+	var raw []map[string]interface{}
+	if err := json.Unmarshal(jsonBuf.Bytes(), &raw); err != nil {
+		t.Fatalf("ExportJSON produced invalid JSON: %v", err)
+	}
+	var runEntry map[string]interface{}
+	for _, entry := range raw {
+		if entry["word"] == "run" {
+			runEntry = entry
+		}
+	}
+	if runEntry == nil {
+		t.Fatalf("ExportJSON output has no entry with lowercase key \"word\" == %q, got %v", "run", raw)
+	}
+	for _, key := range []string{"word", "phonetic", "translation", "exchange"} {
+		if _, ok := runEntry[key]; !ok {
+			t.Errorf("ExportJSON entry missing lowercase key %q, got keys %v", key, runEntry)
+		}
+	}
+	if _, ok := runEntry["Word"]; ok {
+		t.Errorf("ExportJSON entry has PascalCase key %q, want only lowercase keys", "Word")
+	}
+
+	var ndjsonBuf bytes.Buffer
+	if err := d.ExportNDJSON(&ndjsonBuf, ExportOptions{MinFrq: 220}); err != nil {
+		t.Fatalf("ExportNDJSON: %v", err)
+	}
+	lines := strings.Split(strings.TrimSpace(ndjsonBuf.String()), "\n")
+	if len(lines) != 1 {
+		t.Fatalf("ExportNDJSON with MinFrq filter produced %d lines, want 1", len(lines))
+	}
+	var jumpItem exportItem
+	if err := json.Unmarshal([]byte(lines[0]), &jumpItem); err != nil {
+		t.Fatalf("ExportNDJSON line is not valid JSON: %v", err)
+	}
+	if jumpItem.Word != "jump" {
+		t.Errorf("ExportNDJSON MinFrq filter kept %q, want %q", jumpItem.Word, "jump")
+	}
+}
+
+func TestAddEntryAndMerge(t *testing.T) {
+	base := newTestDict(t)
+	base.AddEntry(DictItem{Word: "run", Translation: "overridden"})
+	if got := base.LookUp("run").Translation; got != "overridden" {
+		t.Fatalf("AddEntry should overwrite an existing entry, got translation %q", got)
+	}
+
+	custom, err := NewDict(strings.NewReader(
+		"word,phonetic,definition,translation,pos,collins,oxford,tag,bnc,frq,exchange,detail,audio\n"+
+			"zzyzx,,a made-up word,杜撰词,n,0,0,,,,,,"), nil)
+	if err != nil {
+		t.Fatalf("NewDict: %v", err)
+	}
+
+	base.Merge(custom)
+	if base.LookUp("zzyzx") == nil {
+		t.Fatal("Merge should layer the other Dict's entries on top of base")
+	}
+}
+
+func TestMergeSelfDoesNotDeadlock(t *testing.T) {
+	d := newTestDict(t)
+	done := make(chan struct{})
+	go func() {
+		d.Merge(d)
+		close(done)
+	}()
+	select {
+	case <-done:
+	case <-time.After(2 * time.Second):
+		t.Fatal("d.Merge(d) deadlocked")
+	}
+}
+
+func TestConcurrentMutationAndSearch(t *testing.T) {
+	a := newTestDict(t)
+	b := newTestDict(t)
+
+	var wg sync.WaitGroup
+	stop := make(chan struct{})
+	wg.Add(4)
+
+	go func() {
+		defer wg.Done()
+		for i := 0; ; i++ {
+			select {
+			case <-stop:
+				return
+			default:
+				a.AddEntry(DictItem{Word: "dyn", Frq: "1"})
+			}
+			_ = i
+		}
+	}()
+	go func() {
+		defer wg.Done()
+		for {
+			select {
+			case <-stop:
+				return
+			default:
+				a.Merge(b)
+			}
+		}
+	}()
+	go func() {
+		defer wg.Done()
+		for {
+			select {
+			case <-stop:
+				return
+			default:
+				a.SearchPrefix("ru", 5)
+			}
+		}
+	}()
+	go func() {
+		defer wg.Done()
+		for {
+			select {
+			case <-stop:
+				return
+			default:
+				a.LookUp("run")
+			}
+		}
+	}()
+
+	time.Sleep(50 * time.Millisecond)
+	close(stop)
+	wg.Wait()
+}
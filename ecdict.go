@@ -1,35 +1,30 @@
 package wasmecdict
 
 import (
-	"bufio"
-	"bytes"
-	_ "embed"
 	"encoding/csv"
 	"io"
-	"log"
 	"strings"
 )
 
-//go:embed ecdict.csv
-var ecdictCsv []byte
-
-//go:embed lemma.en.txt
-var lemmaEnTxt []byte
-
 type DictItem struct {
-	Word        string
-	Phonetic    string
-	Definition  string
-	Translation string
-	Pos         string
-	Collins     string
-	Oxford      string
-	Tag         string
-	Bnc         string
-	Frq         string
-	Exchange    string
-	Detail      string
-	Audio       string
+	Word        string `json:"word"`
+	Phonetic    string `json:"phonetic"`
+	Definition  string `json:"definition"`
+	Translation string `json:"translation"`
+	Pos         string `json:"pos"`
+	Collins     string `json:"collins"`
+	Oxford      string `json:"oxford"`
+	Tag         string `json:"tag"`
+	Bnc         string `json:"bnc"`
+	Frq         string `json:"frq"`
+	Exchange    string `json:"exchange"`
+	Detail      string `json:"detail"`
+	Audio       string `json:"audio"`
+
+	// ExchangeMap is the parsed form of Exchange, keyed by exchange code
+	// (p past, d past-participle, i -ing, 3 3rd-sg, s plural, 0 lemma). It
+	// is only populated when requested via LookUpOptions.IncludeExchange.
+	ExchangeMap map[string]string `json:"exchangeMap,omitempty"`
 }
 
 func (d *DictItem) toMap() map[string]interface{} {
@@ -50,36 +45,24 @@ func (d *DictItem) toMap() map[string]interface{} {
 		"exchange":    d.Exchange,
 		"detail":      d.Detail,
 		"audio":       d.Audio,
+		"exchangeMap": d.ExchangeMap,
 	}
 }
 
-var dictMapSingleton = map[string]DictItem{}
-var lemmaMapSingleton = map[string]string{}
-
-func init() {
-	loadDict()
-}
-func loadDict() {
-	if len(dictMapSingleton) == 0 {
-		dictMapSingleton = parseDict()
-	}
-	if len(lemmaMapSingleton) == 0 {
-		lemmaMapSingleton = parseLemma()
-	}
-}
-
-func parseDict() map[string]DictItem {
+// parseDict reads ECDICT's CSV column layout from r into a headword map,
+// indexed by both the word's original casing and its lower-cased form.
+func parseDict(r io.Reader) (map[string]DictItem, error) {
 	dictMap := map[string]DictItem{}
 
-	r := csv.NewReader(bytes.NewReader(ecdictCsv))
+	csvReader := csv.NewReader(r)
 	rowElementCount := 13
 	for {
-		record, err := r.Read()
+		record, err := csvReader.Read()
 		if err == io.EOF {
 			break
 		}
 		if err != nil {
-			log.Fatal(err)
+			return nil, err
 		}
 		if rowElementCount != len(record) {
 			continue
@@ -110,49 +93,32 @@ func parseDict() map[string]DictItem {
 		dictMap[word] = dictItem
 		dictMap[strings.ToLower(word)] = dictItem
 	}
-	return dictMap
+	return dictMap, nil
 }
 func removeBr(w string) string {
 	return strings.ReplaceAll(w, "\\n", "\n")
 }
-func parseLemma() map[string]string {
-	lemmaMap := map[string]string{}
 
-	scanner := bufio.NewScanner(bytes.NewReader(lemmaEnTxt))
-	for scanner.Scan() {
-		line := scanner.Text()
-		line = strings.TrimSpace(line)
-		if strings.HasPrefix(line, ";") {
-			continue
-		}
-		parts := strings.Split(line, " -> ")
-		if len(parts) != 2 {
-			continue
-		}
-		rParts, lParts := strings.Split(parts[0], "/"), strings.Split(parts[1], ",")
-		originalWord := ""
-		if len(rParts) > 0 {
-			originalWord = strings.TrimSpace(rParts[0])
-		}
-		if originalWord == "" {
-			continue
-		}
-		for _, lemma := range lParts {
-			lemma = strings.TrimSpace(lemma)
-			if lemma == "" {
-				continue
-			}
-			lemmaMap[lemma] = originalWord
-		}
-	}
-	return lemmaMap
+// Forms returns every inflected spelling that lemmatizes to base (e.g.
+// Forms("run") might return []string{"running", "ran", "runs"}), in the
+// order they were encountered in the lemma source. It returns nil if base
+// has no recorded inflections.
+func (d *Dict) Forms(base string) []string {
+	d.mu.RLock()
+	defer d.mu.RUnlock()
+	return d.lemmaReverse[strings.TrimSpace(base)]
+}
+
+// Forms looks up inflected forms in the default Dict. See (*Dict).Forms.
+func Forms(base string) []string {
+	return defaultDict.Forms(base)
 }
 
 // LookUp searches for a given word in the dictionary and its lemma form.
 // It first trims any leading or trailing spaces from the input word.
-// If the word exists in the lemma map (lemmaMapSingleton), it retrieves the base form of the word.
+// If the word exists in the lemma map, it retrieves the base form of the word.
 // Otherwise, it proceeds with the original word.
-// Then, it attempts to find the base word in the dictionary map (dictMapSingleton).
+// Then, it attempts to find the base word in the dictionary map.
 // If found, it returns a pointer to the corresponding DictItem.
 // If the word or its base form is not found in the dictionary, it returns nil.
 //
@@ -161,16 +127,67 @@ func parseLemma() map[string]string {
 //
 // Returns:
 // - *DictItem: A pointer to the dictionary item if found; otherwise, nil.
-func LookUp(word string) *DictItem {
-	loadDict()                              // Ensure the dictionary is loaded before searching.
-	word = strings.TrimSpace(word)          // Trim spaces from the input word.
-	baseWord, ok := lemmaMapSingleton[word] // Check if the word has a base form in the lemma map.
+func (d *Dict) LookUp(word string) *DictItem {
+	return d.LookUpWithOptions(word, LookUpOptions{})
+}
+
+// LookUpOptions customizes the DictItem returned by LookUpWithOptions.
+type LookUpOptions struct {
+	// IncludeExchange parses the raw Exchange column into ExchangeMap.
+	IncludeExchange bool
+}
+
+// LookUpWithOptions behaves exactly like LookUp but additionally lets the
+// caller request derived fields that aren't part of DictItem's plain CSV
+// shape, such as a normalized ExchangeMap.
+func (d *Dict) LookUpWithOptions(word string, opts LookUpOptions) *DictItem {
+	word = strings.TrimSpace(word) // Trim spaces from the input word.
+
+	d.mu.RLock()
+	baseWord, ok := d.lemmaMap[word] // Check if the word has a base form in the lemma map.
 	if !ok {
 		baseWord = word // Use the original word if no base form is found.
 	}
-	dictItem, ok := dictMapSingleton[baseWord] // Look up the base word in the dictionary map.
+	dictItem, ok := d.dictMap[baseWord] // Look up the base word in the dictionary map.
+	d.mu.RUnlock()
 	if !ok {
 		return nil // Return nil if the word is not found in the dictionary.
 	}
+	if opts.IncludeExchange {
+		dictItem.ExchangeMap = parseExchange(dictItem.Exchange)
+	}
 	return &dictItem // Return a pointer to the found dictionary item.
 }
+
+// LookUp searches for word in the default Dict. See (*Dict).LookUp.
+func LookUp(word string) *DictItem {
+	return defaultDict.LookUp(word)
+}
+
+// LookUpWithOptions searches for word in the default Dict. See
+// (*Dict).LookUpWithOptions.
+func LookUpWithOptions(word string, opts LookUpOptions) *DictItem {
+	return defaultDict.LookUpWithOptions(word, opts)
+}
+
+// parseExchange normalizes ECDICT's raw Exchange column, a slash-separated
+// list of "code:word" pairs (e.g. "p:ran/d:run/i:running/3:runs/0:run"),
+// into a map keyed by exchange code: p past, d past-participle, i -ing,
+// 3 3rd-sg, s plural, 0 lemma.
+func parseExchange(raw string) map[string]string {
+	if raw == "" {
+		return nil
+	}
+	result := map[string]string{}
+	for _, pair := range strings.Split(raw, "/") {
+		code, word, ok := strings.Cut(pair, ":")
+		if !ok || word == "" {
+			continue
+		}
+		result[code] = word
+	}
+	if len(result) == 0 {
+		return nil
+	}
+	return result
+}
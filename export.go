@@ -0,0 +1,140 @@
+package wasmecdict
+
+import (
+	"encoding/json"
+	"io"
+	"sort"
+	"strconv"
+	"strings"
+)
+
+// ExportOptions filters and shapes the entries produced by ExportJSON and
+// ExportNDJSON. A zero-value ExportOptions exports every entry unfiltered.
+type ExportOptions struct {
+	MinFrq            int      // skip entries whose Frq is below this rank, 0 disables the filter
+	Tags              []string // keep only entries whose Tag field contains one of these (case-insensitive)
+	IncludeLemmaForms bool     // attach every inflected form that lemmatizes to this entry's Word
+}
+
+// exportItem is the shape written for each dictionary entry; it embeds
+// DictItem so the JSON output stays compatible with existing consumers of
+// DictItem's fields and only adds LemmaForms when requested.
+type exportItem struct {
+	DictItem
+	LemmaForms []string `json:"lemmaForms,omitempty"`
+}
+
+// ExportJSON writes every entry of d matching opts to w as a single JSON
+// array, encoding one entry at a time so the full dictionary never has to
+// be held in memory as one giant slice.
+func (d *Dict) ExportJSON(w io.Writer, opts ExportOptions) error {
+	if _, err := io.WriteString(w, "["); err != nil {
+		return err
+	}
+	first := true
+	err := d.visitExportItems(opts, func(item exportItem) error {
+		if !first {
+			if _, err := io.WriteString(w, ","); err != nil {
+				return err
+			}
+		}
+		first = false
+		b, err := json.Marshal(item)
+		if err != nil {
+			return err
+		}
+		_, err = w.Write(b)
+		return err
+	})
+	if err != nil {
+		return err
+	}
+	_, err = io.WriteString(w, "]")
+	return err
+}
+
+// ExportNDJSON writes every entry of d matching opts to w as
+// newline-delimited JSON, one entry per line, streamed as it is produced.
+func (d *Dict) ExportNDJSON(w io.Writer, opts ExportOptions) error {
+	return d.visitExportItems(opts, func(item exportItem) error {
+		b, err := json.Marshal(item)
+		if err != nil {
+			return err
+		}
+		if _, err := w.Write(b); err != nil {
+			return err
+		}
+		_, err = io.WriteString(w, "\n")
+		return err
+	})
+}
+
+// visitExportItems walks d in sorted word order, skipping entries filtered
+// out by opts and the duplicate lower-case keys that dictMap keeps
+// alongside each original-cased entry.
+func (d *Dict) visitExportItems(opts ExportOptions, visit func(exportItem) error) error {
+	d.mu.RLock()
+	words := make([]string, 0, len(d.dictMap))
+	seen := make(map[string]bool, len(d.dictMap))
+	for _, item := range d.dictMap {
+		if seen[item.Word] {
+			continue
+		}
+		seen[item.Word] = true
+		words = append(words, item.Word)
+	}
+	sort.Strings(words)
+	d.mu.RUnlock()
+
+	for _, word := range words {
+		d.mu.RLock()
+		item, ok := d.dictMap[word]
+		d.mu.RUnlock()
+		if !ok || !matchesExportOptions(item, opts) {
+			continue
+		}
+		out := exportItem{DictItem: item}
+		if opts.IncludeLemmaForms {
+			out.LemmaForms = d.Forms(item.Word)
+		}
+		if err := visit(out); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func matchesExportOptions(item DictItem, opts ExportOptions) bool {
+	if opts.MinFrq > 0 {
+		frq, err := strconv.Atoi(strings.TrimSpace(item.Frq))
+		if err != nil || frq < opts.MinFrq {
+			return false
+		}
+	}
+	if len(opts.Tags) > 0 {
+		itemTags := strings.Fields(item.Tag)
+		matched := false
+		for _, want := range opts.Tags {
+			for _, have := range itemTags {
+				if strings.EqualFold(want, have) {
+					matched = true
+					break
+				}
+			}
+		}
+		if !matched {
+			return false
+		}
+	}
+	return true
+}
+
+// ExportJSON exports the default Dict. See (*Dict).ExportJSON.
+func ExportJSON(w io.Writer, opts ExportOptions) error {
+	return defaultDict.ExportJSON(w, opts)
+}
+
+// ExportNDJSON exports the default Dict. See (*Dict).ExportNDJSON.
+func ExportNDJSON(w io.Writer, opts ExportOptions) error {
+	return defaultDict.ExportNDJSON(w, opts)
+}
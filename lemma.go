@@ -0,0 +1,100 @@
+package wasmecdict
+
+import (
+	"bufio"
+	"io"
+	"strconv"
+	"strings"
+)
+
+// LemmaForm is a single inflected spelling of a LemmaEntry's Base.
+type LemmaForm struct {
+	Text string
+	// Irregular marks a form carried an "@" marker in lemma.en.txt,
+	// meaning it is an irregular inflection rather than a regular one
+	// derivable by a suffix rule.
+	Irregular bool
+}
+
+// LemmaEntry is the parsed form of one lemma.en.txt entry: a headword, its
+// frequency rank, and every inflected form recorded for it.
+type LemmaEntry struct {
+	Base  string
+	Rank  int
+	Forms []LemmaForm
+}
+
+// parseLemma reads lemma.en.txt-formatted data from r. Each line has the
+// shape "base/rank -> form1,form2@,...", where rank is a frequency rank and
+// a trailing "@" on a form marks it irregular. The "/rank" part is optional;
+// without it, or when it isn't a number, the entry's Rank is left as it was
+// (0 for a new entry). Lines
+// starting with ";" are comments, and lines with no " -> " or an empty base
+// are skipped. It returns the
+// base->LemmaEntry map plus two views derived from it for quick lookup: the
+// flattened inflected->base map and the base->inflected-forms map.
+func parseLemma(r io.Reader) (map[string]string, map[string][]string, map[string]*LemmaEntry) {
+	entries := map[string]*LemmaEntry{}
+
+	scanner := bufio.NewScanner(r)
+	for scanner.Scan() {
+		line := strings.TrimSpace(scanner.Text())
+		if line == "" || strings.HasPrefix(line, ";") {
+			continue
+		}
+		head, formsPart, ok := strings.Cut(line, " -> ")
+		if !ok {
+			continue
+		}
+		base, rankText, _ := strings.Cut(head, "/")
+		base = strings.TrimSpace(base)
+		if base == "" {
+			continue
+		}
+
+		entry := entries[base]
+		if entry == nil {
+			entry = &LemmaEntry{Base: base}
+			entries[base] = entry
+		}
+		if rank, err := strconv.Atoi(strings.TrimSpace(rankText)); err == nil {
+			entry.Rank = rank
+		}
+
+		for _, rawForm := range strings.Split(formsPart, ",") {
+			text := strings.TrimSpace(rawForm)
+			if text == "" {
+				continue
+			}
+			irregular := strings.HasSuffix(text, "@")
+			if irregular {
+				text = strings.TrimSuffix(text, "@")
+			}
+			entry.Forms = append(entry.Forms, LemmaForm{Text: text, Irregular: irregular})
+		}
+	}
+
+	lemmaMap := map[string]string{}
+	reverseMap := map[string][]string{}
+	for base, entry := range entries {
+		for _, form := range entry.Forms {
+			lemmaMap[form.Text] = base
+			reverseMap[base] = append(reverseMap[base], form.Text)
+		}
+	}
+	return lemmaMap, reverseMap, entries
+}
+
+// Lemma returns the parsed lemma entry for base (its frequency rank and
+// every inflected form with its irregular marker), or nil if base has no
+// recorded entry in the lemma source.
+func (d *Dict) Lemma(base string) *LemmaEntry {
+	d.mu.RLock()
+	defer d.mu.RUnlock()
+	return d.lemmaEntries[strings.TrimSpace(base)]
+}
+
+// Lemma looks up base in the default Dict. See (*Dict).Lemma.
+func Lemma(base string) *LemmaEntry {
+	return defaultDict.Lemma(base)
+}
@@ -0,0 +1,188 @@
+package wasmecdict
+
+import (
+	"index/suffixarray"
+	"sort"
+	"strings"
+)
+
+// wordDelimiter separates headwords in the flattened suffix array buffer.
+// It must never appear inside a headword itself.
+const wordDelimiter = '\n'
+
+// buildSuffixIndexLocked concatenates every distinct headword into a single
+// delimited byte slice and indexes it with index/suffixarray. It is built
+// at most once per index generation (see ensureSuffixIndex), since the
+// index holds a full copy of every headword and is only worth the memory
+// if search is actually used. Callers must hold d.mu for writing.
+func (d *Dict) buildSuffixIndexLocked() {
+	seen := make(map[string]bool, len(d.dictMap))
+	words := make([]string, 0, len(d.dictMap))
+	for word := range d.dictMap {
+		lower := strings.ToLower(word)
+		if seen[lower] {
+			continue
+		}
+		seen[lower] = true
+		words = append(words, lower)
+	}
+	sort.Strings(words)
+
+	var buf strings.Builder
+	starts := make([]int, 0, len(words))
+	for _, word := range words {
+		starts = append(starts, buf.Len())
+		buf.WriteString(word)
+		buf.WriteByte(wordDelimiter)
+	}
+
+	d.suffixStarts = starts
+	d.suffixWords = words
+	d.suffixIndex = suffixarray.New([]byte(buf.String()))
+}
+
+// wordAt returns the headword containing byte offset pos in the indexed
+// buffer and whether pos falls exactly on that word's start offset, via
+// binary search over the sorted word start offsets.
+func (d *Dict) wordAt(pos int) (word string, atStart bool) {
+	i := sort.SearchInts(d.suffixStarts, pos+1) - 1
+	if i < 0 || i >= len(d.suffixWords) {
+		return "", false
+	}
+	return d.suffixWords[i], d.suffixStarts[i] == pos
+}
+
+// suffixLookupStartMultiplier and suffixLookupMaxMultiplier bound how many
+// raw positions we ask index/suffixarray for. A single raw position maps
+// to at most one headword, but several raw positions can collapse onto the
+// same headword (e.g. the substring "an" occurs twice inside "banana", so
+// two raw positions yield one word) and positions that don't satisfy accept
+// (e.g. aren't at a word start for prefix search) are dropped — so we ask
+// for more raw positions than limit and refine upward if that still isn't
+// enough, instead of ever requesting every match in the index.
+const (
+	suffixLookupStartMultiplier = 4
+	suffixLookupMaxMultiplier   = 64
+)
+
+// maxSearchLimit caps the limit a caller can request from SearchPrefix or
+// SearchSubstring. Without this, a limit straight from an untrusted WASM
+// caller (JS numbers aren't bounded by Go's int semantics) would size the
+// result allocation in collectItems directly, letting a single call to
+// e.g. SearchPrefix("r", 1<<30) exhaust the process's memory.
+const maxSearchLimit = 1000
+
+// suffixLookup returns up to limit distinct headwords accepted by accept,
+// without ever pulling every matching position of needle out of the suffix
+// index at once: it asks suffixarray.Index.Lookup for a bounded number of
+// positions, growing that bound only if the first batch didn't yield
+// enough accepted, distinct words.
+func (d *Dict) suffixLookup(needle string, limit int, accept func(pos int) (word string, ok bool)) map[string]bool {
+	matches := map[string]bool{}
+	for mult := suffixLookupStartMultiplier; ; mult *= 4 {
+		n := limit * mult
+		positions := d.suffixIndex.Lookup([]byte(needle), n)
+		matches = map[string]bool{}
+		for _, pos := range positions {
+			word, ok := accept(pos)
+			if !ok {
+				continue
+			}
+			matches[word] = true
+			if len(matches) >= limit {
+				return matches
+			}
+		}
+		if len(positions) < n || mult >= suffixLookupMaxMultiplier {
+			// Lookup handed back fewer positions than we asked for, so
+			// there's nothing more to find, or we've grown the request
+			// enough times that asking again isn't worth the memory.
+			return matches
+		}
+	}
+}
+
+// SearchPrefix returns up to limit dictionary entries whose headword begins
+// with prefix, clamped to maxSearchLimit. The search index is built lazily
+// on first call (see ensureSuffixIndex) and cached until the Dict is next
+// mutated; limit also bounds how many raw suffix-array positions a lookup
+// can pull at once, so the WASM heap stays predictable even for a prefix
+// shared by most of the dictionary.
+func (d *Dict) SearchPrefix(prefix string, limit int) []*DictItem {
+	prefix = strings.ToLower(strings.TrimSpace(prefix))
+	if prefix == "" || limit <= 0 {
+		return nil
+	}
+	limit = clampSearchLimit(limit)
+	matches := d.withSuffixIndex(func() map[string]bool {
+		return d.suffixLookup(prefix, limit, func(pos int) (string, bool) {
+			word, atStart := d.wordAt(pos)
+			return word, word != "" && atStart
+		})
+	})
+	return d.collectItems(matches, limit)
+}
+
+// SearchSubstring returns up to limit dictionary entries whose headword
+// contains substr anywhere. See SearchPrefix for index construction and
+// the limit semantics.
+func (d *Dict) SearchSubstring(substr string, limit int) []*DictItem {
+	substr = strings.ToLower(strings.TrimSpace(substr))
+	if substr == "" || limit <= 0 {
+		return nil
+	}
+	limit = clampSearchLimit(limit)
+	matches := d.withSuffixIndex(func() map[string]bool {
+		return d.suffixLookup(substr, limit, func(pos int) (string, bool) {
+			word, _ := d.wordAt(pos)
+			return word, word != ""
+		})
+	})
+	return d.collectItems(matches, limit)
+}
+
+// clampSearchLimit bounds limit to maxSearchLimit so it's safe to use as
+// an allocation size, regardless of what a caller requests.
+func clampSearchLimit(limit int) int {
+	if limit > maxSearchLimit {
+		return maxSearchLimit
+	}
+	return limit
+}
+
+// collectItems resolves a set of lowercased headwords back to DictItems,
+// in sorted word order, truncated to limit entries.
+func (d *Dict) collectItems(matches map[string]bool, limit int) []*DictItem {
+	words := make([]string, 0, len(matches))
+	for word := range matches {
+		words = append(words, word)
+	}
+	sort.Strings(words)
+
+	d.mu.RLock()
+	defer d.mu.RUnlock()
+
+	items := make([]*DictItem, 0, limit)
+	for _, word := range words {
+		if len(items) >= limit {
+			break
+		}
+		dictItem, ok := d.dictMap[word]
+		if !ok {
+			continue
+		}
+		item := dictItem
+		items = append(items, &item)
+	}
+	return items
+}
+
+// SearchPrefix searches the default Dict. See (*Dict).SearchPrefix.
+func SearchPrefix(prefix string, limit int) []*DictItem {
+	return defaultDict.SearchPrefix(prefix, limit)
+}
+
+// SearchSubstring searches the default Dict. See (*Dict).SearchSubstring.
+func SearchSubstring(substr string, limit int) []*DictItem {
+	return defaultDict.SearchSubstring(substr, limit)
+}
@@ -0,0 +1,128 @@
+package wasmecdict
+
+import (
+	"bufio"
+	"io"
+	"strings"
+	"unicode"
+)
+
+// Token is a single recognized word found while scanning free-form text,
+// together with its byte offset and length in the original input and, if
+// it matched a dictionary entry, that entry.
+type Token struct {
+	Surface string
+	Offset  int
+	Length  int
+	Entry   *DictItem
+}
+
+// Tokenize scans text for word-like runs, looks each one up in d (splitting
+// contractions such as "don't" or "it's" into both halves), and returns
+// every recognized token in order. Pure digit runs are skipped, as is
+// anything that isn't a letter, digit, or apostrophe.
+func (d *Dict) Tokenize(text string) []Token {
+	var tokens []Token
+	_ = d.TokenizeReader(strings.NewReader(text), func(t Token) error {
+		tokens = append(tokens, t)
+		return nil
+	})
+	return tokens
+}
+
+// TokenizeReader streams tokens from r to cb as they are found, letting a
+// caller highlight a large document without materializing the full token
+// slice. Scanning stops early, returning cb's error, if cb returns one.
+func (d *Dict) TokenizeReader(r io.Reader, cb func(Token) error) error {
+	br := bufio.NewReader(r)
+	offset := 0
+	wordStart := 0
+	var word strings.Builder
+
+	flush := func() error {
+		if word.Len() == 0 {
+			return nil
+		}
+		surface := word.String()
+		start := wordStart
+		word.Reset()
+		return d.emitToken(surface, start, cb)
+	}
+
+	for {
+		ch, size, err := br.ReadRune()
+		if err == io.EOF {
+			break
+		}
+		if err != nil {
+			return err
+		}
+		if isWordRune(ch) {
+			if word.Len() == 0 {
+				wordStart = offset
+			}
+			word.WriteRune(ch)
+		} else if err := flush(); err != nil {
+			return err
+		}
+		offset += size
+	}
+	return flush()
+}
+
+// isWordRune reports whether r can appear inside a token. Apostrophes are
+// included so contractions are captured as a single run and split apart
+// (and looked up) in emitToken.
+func isWordRune(r rune) bool {
+	return unicode.IsLetter(r) || unicode.IsDigit(r) || r == '\''
+}
+
+// emitToken splits surface on apostrophes so contractions look up both
+// halves, skips pieces that are pure digits, and calls cb once per
+// recognized piece with its offset into the original text.
+func (d *Dict) emitToken(surface string, start int, cb func(Token) error) error {
+	rest := surface
+	pos := start
+	for {
+		idx := strings.IndexByte(rest, '\'')
+		piece := rest
+		if idx != -1 {
+			piece = rest[:idx]
+		}
+		if piece != "" && !isAllDigits(piece) {
+			if err := cb(Token{
+				Surface: piece,
+				Offset:  pos,
+				Length:  len(piece),
+				Entry:   d.LookUp(piece),
+			}); err != nil {
+				return err
+			}
+		}
+		if idx == -1 {
+			return nil
+		}
+		pos += idx + 1
+		rest = rest[idx+1:]
+	}
+}
+
+func isAllDigits(s string) bool {
+	for _, r := range s {
+		if !unicode.IsDigit(r) {
+			return false
+		}
+	}
+	return true
+}
+
+// Tokenize scans text using the default Dict. See (*Dict).Tokenize.
+func Tokenize(text string) []Token {
+	return defaultDict.Tokenize(text)
+}
+
+// TokenizeReader streams tokens from r using the default Dict. See
+// (*Dict).TokenizeReader.
+func TokenizeReader(r io.Reader, cb func(Token) error) error {
+	return defaultDict.TokenizeReader(r, cb)
+}